@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eDyrr/url-shortener/middleware"
+	"github.com/eDyrr/url-shortener/store"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateShortUrlRequest is the expected JSON body for POST /create-short-url.
+// ExpiresIn is optional and given in seconds; when omitted the link falls
+// back to the default lifetime, and when it exceeds store.MaxCacheDuration
+// it is clamped down to that ceiling. Alias is optional; when set, the caller's own slug is used
+// instead of a generated one, failing with 409 if it's already taken.
+type CreateShortUrlRequest struct {
+	URL       string `json:"url" binding:"required"`
+	ExpiresIn int64  `json:"expires_in"`
+	Alias     string `json:"alias"`
+}
+
+// CreateShortUrlResponse is what we hand back once a short link is created
+type CreateShortUrlResponse struct {
+	ShortUrl    string    `json:"shortUrl"`
+	OriginalUrl string    `json:"originalUrl"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// CreateShortUrl reads the original url (plus optional custom lifetime,
+// owning user and alias) from the request body, mints a short code for
+// it - or claims the caller's requested alias - and persists the mapping
+func CreateShortUrl(c *gin.Context) {
+	var request CreateShortUrlRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	expiresIn := time.Duration(request.ExpiresIn) * time.Second
+	userId := middleware.UserId(c)
+
+	var shortUrl string
+	if request.Alias != "" {
+		if err := store.ReserveAlias(request.Alias); err != nil {
+			if errors.Is(err, store.ErrAliasTaken) {
+				c.JSON(http.StatusConflict, gin.H{"message": "alias already in use"})
+				return
+			}
+			panic(err)
+		}
+		shortUrl = request.Alias
+	} else {
+		shortUrl = store.GenerateShortCode()
+	}
+
+	store.SaveUrlMapping(shortUrl, request.URL, userId, expiresIn)
+
+	stats := store.GetUrlStats(shortUrl)
+	c.JSON(http.StatusOK, CreateShortUrlResponse{
+		ShortUrl:    shortUrl,
+		OriginalUrl: request.URL,
+		ExpiresAt:   stats.ExpiresAt,
+	})
+}
+
+// HandleShortUrlRedirects records the visit for analytics purposes and
+// redirects the caller to the original url for the given short code.
+// A temporary redirect is used on purpose so browsers don't cache the
+// redirect and every hit is counted.
+func HandleShortUrlRedirects(c *gin.Context) {
+	shortUrl := c.Param("shortUrl")
+
+	store.RecordVisit(shortUrl, c.Request.Referer(), c.Request.UserAgent(), c.ClientIP())
+
+	initialUrl := store.RetrieveInitialUrl(shortUrl)
+	c.Redirect(http.StatusTemporaryRedirect, initialUrl)
+}
+
+// GetUrlStats returns total clicks, creation/expiration times and the most
+// recent visits recorded for a short code, but only to its owner - the
+// response includes the owner's userId and every visitor's IP, user
+// agent and referer, so it's gated the same way as DeleteShortUrl and
+// UpdateShortUrl
+func GetUrlStats(c *gin.Context) {
+	shortUrl := c.Param("shortUrl")
+	if !requireOwnership(c, shortUrl) {
+		return
+	}
+
+	stats := store.GetUrlStats(shortUrl)
+	c.JSON(http.StatusOK, stats)
+}
+
+// defaultLinksPageSize is used for GET /me/links when the caller doesn't
+// specify a page size
+const defaultLinksPageSize = 20
+
+// ListMyLinks returns a paginated list of short codes owned by the
+// authenticated user, most recently created first
+func ListMyLinks(c *gin.Context) {
+	userId := middleware.UserId(c)
+
+	page, _ := strconv.ParseInt(c.DefaultQuery("page", "0"), 10, 64)
+	if page < 0 {
+		page = 0
+	}
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", strconv.Itoa(defaultLinksPageSize)), 10, 64)
+	if limit <= 0 {
+		limit = defaultLinksPageSize
+	}
+
+	links := store.ListUserLinks(userId, page*limit, limit)
+	c.JSON(http.StatusOK, gin.H{"links": links, "page": page, "limit": limit})
+}
+
+// requireOwnership loads the stats for shortUrl and verifies it exists
+// and belongs to the authenticated user, writing the appropriate error
+// response itself when it doesn't. It reports whether the caller may
+// proceed.
+func requireOwnership(c *gin.Context, shortUrl string) bool {
+	stats := store.GetUrlStats(shortUrl)
+	if stats.OriginalUrl == "" {
+		c.JSON(http.StatusNotFound, gin.H{"message": "short url not found"})
+		return false
+	}
+	if stats.UserId != middleware.UserId(c) {
+		c.JSON(http.StatusForbidden, gin.H{"message": "not the owner of this short url"})
+		return false
+	}
+	return true
+}
+
+// DeleteShortUrl removes a short link, but only when the authenticated
+// user is its owner
+func DeleteShortUrl(c *gin.Context) {
+	shortUrl := c.Param("shortUrl")
+	if !requireOwnership(c, shortUrl) {
+		return
+	}
+
+	store.DeleteUrlMapping(shortUrl)
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateShortUrlRequest is the JSON body accepted by PATCH /:shortUrl.
+// Both fields are optional; an empty URL or non-positive ExpiresIn
+// leaves that field unchanged
+type UpdateShortUrlRequest struct {
+	URL       string `json:"url"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// UpdateShortUrl changes the destination and/or lifetime of a short
+// link, but only when the authenticated user is its owner
+func UpdateShortUrl(c *gin.Context) {
+	shortUrl := c.Param("shortUrl")
+	if !requireOwnership(c, shortUrl) {
+		return
+	}
+
+	var request UpdateShortUrlRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	expiresIn := time.Duration(request.ExpiresIn) * time.Second
+	store.UpdateUrlMapping(shortUrl, request.URL, expiresIn)
+
+	c.JSON(http.StatusOK, store.GetUrlStats(shortUrl))
+}
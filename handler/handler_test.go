@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eDyrr/url-shortener/middleware"
+	"github.com/eDyrr/url-shortener/store"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJwtSecret = "test-secret"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	store.InitializeStore()
+}
+
+func testToken(t *testing.T, userId string) string {
+	claims := jwt.MapClaims{"sub": userId, "exp": time.Now().Add(time.Hour).Unix()}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJwtSecret))
+	assert.NoError(t, err)
+	return signed
+}
+
+// ownershipTestRouter mounts the same handlers main.go puts behind
+// RequireAuth, so these tests exercise the real 404-vs-403 ownership
+// behavior end to end rather than calling requireOwnership directly
+func ownershipTestRouter() *gin.Engine {
+	r := gin.New()
+	authorized := r.Group("/")
+	authorized.Use(middleware.RequireAuth(testJwtSecret))
+	{
+		authorized.GET("/stats/:shortUrl", GetUrlStats)
+		authorized.DELETE("/:shortUrl", DeleteShortUrl)
+		authorized.PATCH("/:shortUrl", UpdateShortUrl)
+	}
+	return r
+}
+
+func doOwnershipRequest(r *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetUrlStatsEnforcesOwnership(t *testing.T) {
+	r := ownershipTestRouter()
+	ownerId, otherId := "owner-stats", "other-stats"
+	shortUrl := "HndlStats1"
+	store.SaveUrlMapping(shortUrl, "https://example.com", ownerId, store.DefaultCacheDuration)
+
+	rec := doOwnershipRequest(r, http.MethodGet, "/stats/"+shortUrl, testToken(t, ownerId), "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doOwnershipRequest(r, http.MethodGet, "/stats/"+shortUrl, testToken(t, otherId), "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doOwnershipRequest(r, http.MethodGet, "/stats/DoesNotExist", testToken(t, ownerId), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeleteShortUrlEnforcesOwnership(t *testing.T) {
+	r := ownershipTestRouter()
+	ownerId, otherId := "owner-delete", "other-delete"
+	shortUrl := "HndlDel001"
+	store.SaveUrlMapping(shortUrl, "https://example.com", ownerId, store.DefaultCacheDuration)
+
+	rec := doOwnershipRequest(r, http.MethodDelete, "/"+shortUrl, testToken(t, otherId), "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doOwnershipRequest(r, http.MethodDelete, "/DoesNotExist", testToken(t, ownerId), "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doOwnershipRequest(r, http.MethodDelete, "/"+shortUrl, testToken(t, ownerId), "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestUpdateShortUrlEnforcesOwnership(t *testing.T) {
+	r := ownershipTestRouter()
+	ownerId, otherId := "owner-update", "other-update"
+	shortUrl := "HndlUpd001"
+	store.SaveUrlMapping(shortUrl, "https://example.com", ownerId, store.DefaultCacheDuration)
+
+	body := `{"url":"https://example.com/updated"}`
+	rec := doOwnershipRequest(r, http.MethodPatch, "/"+shortUrl, testToken(t, otherId), body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doOwnershipRequest(r, http.MethodPatch, "/DoesNotExist", testToken(t, ownerId), body)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doOwnershipRequest(r, http.MethodPatch, "/"+shortUrl, testToken(t, ownerId), body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
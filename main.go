@@ -3,14 +3,28 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/eDyrr/url-shortener/handler"
+	"github.com/eDyrr/url-shortener/middleware"
 	"github.com/eDyrr/url-shortener/store"
 	"github.com/gin-gonic/gin"
 )
 
+// jwtSecret returns the signing secret management routes authenticate
+// against. It falls back to a development default so the server still
+// boots locally without extra setup, but that default should never be
+// used in production.
+func jwtSecret() string {
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return secret
+	}
+	return "dev-secret"
+}
+
 func main() {
-	r := gin.Default()
+	r := gin.New()
+	r.Use(middleware.Logger(), middleware.Recovery())
 
 	r.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -18,14 +32,34 @@ func main() {
 		})
 	})
 
-	r.POST("/create-short-url", func(c *gin.Context) {
-		handler.CreateShortUrl(c)
-	})
-
 	r.GET("/:shortUrl", func(c *gin.Context) {
 		handler.HandleShortUrlRedirects(c)
 	})
 
+	authorized := r.Group("/")
+	authorized.Use(middleware.RequireAuth(jwtSecret()))
+	{
+		authorized.POST("/create-short-url", func(c *gin.Context) {
+			handler.CreateShortUrl(c)
+		})
+
+		authorized.GET("/me/links", func(c *gin.Context) {
+			handler.ListMyLinks(c)
+		})
+
+		authorized.GET("/stats/:shortUrl", func(c *gin.Context) {
+			handler.GetUrlStats(c)
+		})
+
+		authorized.DELETE("/:shortUrl", func(c *gin.Context) {
+			handler.DeleteShortUrl(c)
+		})
+
+		authorized.PATCH("/:shortUrl", func(c *gin.Context) {
+			handler.UpdateShortUrl(c)
+		})
+	}
+
 	store.InitializeStore()
 	err := r.Run(":9808")
 	if err != nil {
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// userIdContextKey is where RequireAuth stashes the authenticated user id
+// for downstream handlers to read via UserId
+const userIdContextKey = "userId"
+
+// RequireAuth validates the bearer JWT on the request, using secret to
+// verify its signature, and stores the token's subject claim as the
+// authenticated user id for downstream handlers. Requests without a
+// valid token are aborted with 401 before reaching the handler
+func RequireAuth(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString := strings.TrimPrefix(header, "Bearer ")
+		if tokenString == "" || tokenString == header {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "missing bearer token"})
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(secret), nil
+		})
+		if err != nil || !token.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "invalid or expired token"})
+			return
+		}
+
+		userId, ok := claims["sub"].(string)
+		if !ok || userId == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "token missing subject claim"})
+			return
+		}
+
+		c.Set(userIdContextKey, userId)
+		c.Next()
+	}
+}
+
+// UserId returns the authenticated user id stashed by RequireAuth. It
+// must only be called on routes mounted behind that middleware
+func UserId(c *gin.Context) string {
+	userId, _ := c.Get(userIdContextKey)
+	id, _ := userId.(string)
+	return id
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "test-secret"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func authRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(RequireAuth(testSecret))
+	r.GET("/whoami", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"userId": UserId(c)})
+	})
+	return r
+}
+
+func doAuthRequest(token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	authRouter().ServeHTTP(rec, req)
+	return rec
+}
+
+func hmacToken(t *testing.T, secret string, userId string, expiresIn time.Duration) string {
+	claims := jwt.MapClaims{"sub": userId, "exp": time.Now().Add(expiresIn).Unix()}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestRequireAuthAcceptsValidToken(t *testing.T) {
+	rec := doAuthRequest(hmacToken(t, testSecret, "user-1", time.Hour))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "user-1")
+}
+
+func TestRequireAuthRejectsMissingToken(t *testing.T) {
+	rec := doAuthRequest("")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireAuthRejectsWrongSecret(t *testing.T) {
+	rec := doAuthRequest(hmacToken(t, "wrong-secret", "user-1", time.Hour))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireAuthRejectsExpiredToken(t *testing.T) {
+	rec := doAuthRequest(hmacToken(t, testSecret, "user-1", -time.Hour))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// TestRequireAuthRejectsNonHmacSigningMethod guards against algorithm
+// confusion: a token signed with an asymmetric method must never be
+// accepted just because its claims look valid
+func TestRequireAuthRejectsNonHmacSigningMethod(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	assert.NoError(t, err)
+
+	rec := doAuthRequest(signed)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery converts a panic anywhere downstream - most commonly the store
+// layer panicking on a Redis or Postgres error - into a plain 500
+// response instead of crashing the process
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("recovered from panic: %v", r)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "internal server error"})
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned by a Backend when a short code has no mapping,
+// either because it was never created or because it has expired
+var ErrNotFound = errors.New("store: short url not found")
+
+// LinkMetadata is the full record a Backend holds for a short code,
+// beyond just the redirect target - used to rehydrate the Redis cache's
+// metadata hash after a primary-store fallback
+type LinkMetadata struct {
+	OriginalUrl string
+	UserId      string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Backend is implemented by anything that can durably store and look up
+// the shortUrl -> originalUrl mapping. RedisStore is the fast, TTL-backed
+// implementation used as the read-through cache; PostgresStore is the
+// durable backing store. Method names are deliberately short and generic
+// (Put/Get/Delete) rather than echoing the package-level
+// SaveUrlMapping/RetrieveInitialUrl/DeleteUrlMapping functions that
+// orchestrate the two tiers, so a call through one can't be mistaken for
+// a call through the other
+type Backend interface {
+	Put(shortUrl string, originalUrl string, userId string, expiresIn time.Duration) error
+	Get(shortUrl string) (string, error)
+	GetMetadata(shortUrl string) (LinkMetadata, error)
+	Delete(shortUrl string) error
+}
+
+// RedisStore implements Backend on top of a RedisDriver, mirroring the
+// original single-tier behaviour: the mapping lives as a plain string
+// key with a TTL. The underlying driver (go-redis or rueidis) is
+// pluggable and chosen at startup
+type RedisStore struct {
+	driver RedisDriver
+}
+
+func NewRedisStore(driver RedisDriver) *RedisStore {
+	return &RedisStore{driver: driver}
+}
+
+func (s *RedisStore) Put(shortUrl string, originalUrl string, userId string, expiresIn time.Duration) error {
+	if err := s.driver.Set(shortUrl, originalUrl, expiresIn); err != nil {
+		return fmt.Errorf("redis store: failed saving key url: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Get(shortUrl string) (string, error) {
+	result, err := s.driver.Get(shortUrl)
+	if errors.Is(err, ErrNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("redis store: failed retrieving url: %w", err)
+	}
+	return result, nil
+}
+
+func (s *RedisStore) GetMetadata(shortUrl string) (LinkMetadata, error) {
+	fields, err := s.driver.HGetAll(metadataKey(shortUrl))
+	if err != nil {
+		return LinkMetadata{}, fmt.Errorf("redis store: failed retrieving metadata: %w", err)
+	}
+	if fields["originalUrl"] == "" {
+		return LinkMetadata{}, ErrNotFound
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, fields["createdAt"])
+	expiresAt, _ := time.Parse(time.RFC3339, fields["expiresAt"])
+	return LinkMetadata{
+		OriginalUrl: fields["originalUrl"],
+		UserId:      fields["userId"],
+		CreatedAt:   createdAt,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (s *RedisStore) Delete(shortUrl string) error {
+	if err := s.driver.Del(shortUrl); err != nil {
+		return fmt.Errorf("redis store: failed deleting key url: %w", err)
+	}
+	return nil
+}
+
+var _ Backend = (*RedisStore)(nil)
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// GoRedisDriver is the default RedisDriver implementation, built directly
+// on top of go-redis/v8
+type GoRedisDriver struct {
+	client *redis.Client
+}
+
+// NewGoRedisDriver dials Redis at addr and verifies the connection with a
+// Ping before handing back a usable driver
+func NewGoRedisDriver(addr string, password string) (*GoRedisDriver, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       0,
+	})
+
+	if _, err := client.Ping(ctx).Result(); err != nil {
+		return nil, fmt.Errorf("go-redis driver: failed pinging redis: %w", err)
+	}
+	return &GoRedisDriver{client: client}, nil
+}
+
+func (d *GoRedisDriver) Get(key string) (string, error) {
+	result, err := d.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return result, err
+}
+
+func (d *GoRedisDriver) Set(key string, value string, ttl time.Duration) error {
+	return d.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (d *GoRedisDriver) SetNX(key string, value string, ttl time.Duration) (bool, error) {
+	return d.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (d *GoRedisDriver) Incr(key string) (int64, error) {
+	return d.client.Incr(ctx, key).Result()
+}
+
+func (d *GoRedisDriver) HSet(key string, fields map[string]interface{}) error {
+	return d.client.HSet(ctx, key, fields).Err()
+}
+
+func (d *GoRedisDriver) HGetAll(key string) (map[string]string, error) {
+	return d.client.HGetAll(ctx, key).Result()
+}
+
+func (d *GoRedisDriver) Expire(key string, ttl time.Duration) error {
+	return d.client.Expire(ctx, key, ttl).Err()
+}
+
+func (d *GoRedisDriver) LPush(key string, value string) error {
+	return d.client.LPush(ctx, key, value).Err()
+}
+
+func (d *GoRedisDriver) LTrim(key string, start int64, stop int64) error {
+	return d.client.LTrim(ctx, key, start, stop).Err()
+}
+
+func (d *GoRedisDriver) LRange(key string, start int64, stop int64) ([]string, error) {
+	return d.client.LRange(ctx, key, start, stop).Result()
+}
+
+func (d *GoRedisDriver) Del(key string) error {
+	return d.client.Del(ctx, key).Err()
+}
+
+func (d *GoRedisDriver) ZAdd(key string, score float64, member string) error {
+	return d.client.ZAdd(ctx, key, &redis.Z{Score: score, Member: member}).Err()
+}
+
+func (d *GoRedisDriver) ZRem(key string, member string) error {
+	return d.client.ZRem(ctx, key, member).Err()
+}
+
+func (d *GoRedisDriver) ZRevRange(key string, start int64, stop int64) ([]string, error) {
+	return d.client.ZRevRange(ctx, key, start, stop).Result()
+}
+
+// SaveMapping batches the main key and metadata hash writes into a single
+// pipelined round trip rather than issuing Set/HSet/Expire separately
+func (d *GoRedisDriver) SaveMapping(write MappingWrite) error {
+	pipe := d.client.Pipeline()
+	pipe.Set(ctx, write.Key, write.Value, write.TTL)
+	pipe.HSet(ctx, write.MetaKey, write.MetaFields)
+	pipe.Expire(ctx, write.MetaKey, write.TTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return mappingWriteError(err)
+	}
+	return nil
+}
+
+var _ RedisDriver = (*GoRedisDriver)(nil)
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresStore is the durable backing store: every short link is written
+// here first, and Get falls back to it whenever the Redis cache has
+// evicted or never held the mapping
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore opens the connection, pings it and makes sure the
+// url_mappings table exists before handing back a usable store
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("postgres store: failed opening connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("postgres store: failed pinging database: %w", err)
+	}
+
+	store := &PostgresStore{db: db}
+	if err := store.migrate(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *PostgresStore) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS url_mappings (
+			short_url    TEXT PRIMARY KEY,
+			original_url TEXT NOT NULL,
+			user_id      TEXT NOT NULL DEFAULT '',
+			created_at   TIMESTAMPTZ NOT NULL,
+			expires_at   TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("postgres store: failed running migration: %w", err)
+	}
+	return nil
+}
+
+// Put inserts a new mapping, or updates an existing one in place. On
+// conflict created_at is deliberately left alone so an update never
+// resets a link's original creation time
+func (s *PostgresStore) Put(shortUrl string, originalUrl string, userId string, expiresIn time.Duration) error {
+	now := time.Now().UTC()
+	_, err := s.db.Exec(`
+		INSERT INTO url_mappings (short_url, original_url, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (short_url) DO UPDATE
+		SET original_url = EXCLUDED.original_url,
+			user_id = EXCLUDED.user_id,
+			expires_at = EXCLUDED.expires_at
+	`, shortUrl, originalUrl, userId, now, now.Add(expiresIn))
+	if err != nil {
+		return fmt.Errorf("postgres store: failed saving url mapping: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Get(shortUrl string) (string, error) {
+	var originalUrl string
+	err := s.db.QueryRow(`
+		SELECT original_url FROM url_mappings
+		WHERE short_url = $1 AND expires_at > now()
+	`, shortUrl).Scan(&originalUrl)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("postgres store: failed retrieving url mapping: %w", err)
+	}
+	return originalUrl, nil
+}
+
+// GetMetadata reads the full row for shortUrl, used to rehydrate the
+// Redis metadata hash after a cache-miss fallback to Postgres
+func (s *PostgresStore) GetMetadata(shortUrl string) (LinkMetadata, error) {
+	var metadata LinkMetadata
+	err := s.db.QueryRow(`
+		SELECT original_url, user_id, created_at, expires_at FROM url_mappings
+		WHERE short_url = $1 AND expires_at > now()
+	`, shortUrl).Scan(&metadata.OriginalUrl, &metadata.UserId, &metadata.CreatedAt, &metadata.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return LinkMetadata{}, ErrNotFound
+	}
+	if err != nil {
+		return LinkMetadata{}, fmt.Errorf("postgres store: failed retrieving url metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func (s *PostgresStore) Delete(shortUrl string) error {
+	_, err := s.db.Exec(`DELETE FROM url_mappings WHERE short_url = $1`, shortUrl)
+	if err != nil {
+		return fmt.Errorf("postgres store: failed deleting url mapping: %w", err)
+	}
+	return nil
+}
+
+var _ Backend = (*PostgresStore)(nil)
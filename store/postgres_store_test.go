@@ -0,0 +1,147 @@
+package store
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// postgresTestDSN returns the DSN to use for Postgres-backed tests, read
+// from POSTGRES_TEST_DSN. Unlike Redis, Postgres is optional
+// infrastructure for this service, so tests that need it are skipped
+// rather than failing when it isn't configured
+func postgresTestDSN(t *testing.T) string {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set, skipping Postgres-backed test")
+	}
+	return dsn
+}
+
+func TestPostgresStorePutGetDelete(t *testing.T) {
+	pgStore, err := NewPostgresStore(postgresTestDSN(t))
+	assert.NoError(t, err)
+
+	shortUrl := "PgTest0001"
+	assert.NoError(t, pgStore.Put(shortUrl, "https://example.com", "user-1", time.Hour))
+
+	url, err := pgStore.Get(shortUrl)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", url)
+
+	metadata, err := pgStore.GetMetadata(shortUrl)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", metadata.OriginalUrl)
+	assert.Equal(t, "user-1", metadata.UserId)
+
+	assert.NoError(t, pgStore.Delete(shortUrl))
+	_, err = pgStore.Get(shortUrl)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPostgresStorePutPreservesCreatedAtOnUpdate(t *testing.T) {
+	pgStore, err := NewPostgresStore(postgresTestDSN(t))
+	assert.NoError(t, err)
+
+	shortUrl := "PgTest0002"
+	assert.NoError(t, pgStore.Put(shortUrl, "https://example.com", "user-1", time.Hour))
+	original, err := pgStore.GetMetadata(shortUrl)
+	assert.NoError(t, err)
+
+	assert.NoError(t, pgStore.Put(shortUrl, "https://example.com/updated", "user-1", time.Hour))
+	updated, err := pgStore.GetMetadata(shortUrl)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/updated", updated.OriginalUrl)
+	assert.Equal(t, original.CreatedAt, updated.CreatedAt)
+}
+
+func TestPostgresStoreGetExpired(t *testing.T) {
+	pgStore, err := NewPostgresStore(postgresTestDSN(t))
+	assert.NoError(t, err)
+
+	shortUrl := "PgTest0003"
+	assert.NoError(t, pgStore.Put(shortUrl, "https://example.com", "user-1", -time.Hour))
+
+	_, err = pgStore.Get(shortUrl)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = pgStore.GetMetadata(shortUrl)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestInitializeStoreUsesPostgresAsPrimaryWhenConfigured exercises the
+// POSTGRES_DSN branch of InitializeStore: when it's set, Redis is wired
+// up purely as a cache in front of a distinct Postgres primary, rather
+// than the cache-only fallback used when it's unset
+func TestInitializeStoreUsesPostgresAsPrimaryWhenConfigured(t *testing.T) {
+	os.Setenv("POSTGRES_DSN", postgresTestDSN(t))
+	defer os.Unsetenv("POSTGRES_DSN")
+
+	service := InitializeStore()
+	assert.NotSame(t, service.primary, service.cache)
+}
+
+// TestRetrieveInitialUrlRepopulatesCacheMetadataFromPrimary exercises the
+// cache-miss path: once the Redis cache has lost a mapping, the primary
+// store's record must be enough to both redirect correctly and rebuild
+// the full metadata hash - not just the plain key - so GetUrlStats and
+// requireOwnership keep working afterwards
+func TestRetrieveInitialUrlRepopulatesCacheMetadataFromPrimary(t *testing.T) {
+	pgStore, err := NewPostgresStore(postgresTestDSN(t))
+	assert.NoError(t, err)
+
+	originalPrimary := storeService.primary
+	storeService.primary = pgStore
+	defer func() { storeService.primary = originalPrimary }()
+
+	shortUrl := "PgTest0004"
+	userUUId := "e0dba740-fc4b-4977-872c-d360239e6b1a"
+	SaveUrlMapping(shortUrl, "https://example.com/repopulate", userUUId, DefaultCacheDuration)
+
+	// simulate the Redis cache entry and metadata hash expiring
+	// independently of the still-live Postgres row
+	assert.NoError(t, storeService.driver.Del(shortUrl))
+	assert.NoError(t, storeService.driver.Del(metadataKey(shortUrl)))
+
+	retrieved := RetrieveInitialUrl(shortUrl)
+	assert.Equal(t, "https://example.com/repopulate", retrieved)
+
+	stats := GetUrlStats(shortUrl)
+	assert.Equal(t, "https://example.com/repopulate", stats.OriginalUrl)
+	assert.Equal(t, userUUId, stats.UserId)
+}
+
+// TestGetUrlStatsRepopulatesCacheMetadataFromPrimary exercises the same
+// cache-miss scenario directly against GetUrlStats, without going through
+// RetrieveInitialUrl first. GET /stats/:shortUrl and the requireOwnership
+// check behind DELETE/PATCH only ever call GetUrlStats, so it has to
+// fall back to the primary store on its own rather than relying on the
+// redirect path having already repopulated the cache
+func TestGetUrlStatsRepopulatesCacheMetadataFromPrimary(t *testing.T) {
+	pgStore, err := NewPostgresStore(postgresTestDSN(t))
+	assert.NoError(t, err)
+
+	originalPrimary := storeService.primary
+	storeService.primary = pgStore
+	defer func() { storeService.primary = originalPrimary }()
+
+	shortUrl := "PgTest0005"
+	userUUId := "e0dba740-fc4b-4977-872c-d360239e6b1a"
+	SaveUrlMapping(shortUrl, "https://example.com/stats-repopulate", userUUId, DefaultCacheDuration)
+
+	// evict only the metadata hash, as if its TTL expired independently
+	// of the still-live Postgres row - the plain redirect key is left
+	// alone so this can't be mistaken for the RetrieveInitialUrl path
+	assert.NoError(t, storeService.driver.Del(metadataKey(shortUrl)))
+
+	stats := GetUrlStats(shortUrl)
+	assert.Equal(t, "https://example.com/stats-repopulate", stats.OriginalUrl)
+	assert.Equal(t, userUUId, stats.UserId)
+
+	DeleteUrlMapping(shortUrl)
+	_, err = pgStore.Get(shortUrl)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClientSideCacheTTL bounds how long the rueidis driver is allowed to
+// keep a value in its in-process, RESP3-tracked client-side cache before
+// it must be revalidated against Redis
+const ClientSideCacheTTL = 10 * time.Second
+
+// MappingWrite describes a short link write: the main shortUrl -> url
+// string key plus its metadata hash, bundled together so a driver can
+// issue them as a single pipelined round trip instead of one call each
+type MappingWrite struct {
+	Key        string
+	Value      string
+	MetaKey    string
+	MetaFields map[string]interface{}
+	TTL        time.Duration
+}
+
+// RedisDriver abstracts the handful of Redis commands the store package
+// needs, so the underlying client - go-redis today, rueidis for hot,
+// read-heavy deployments - can be swapped via config without touching
+// business logic
+type RedisDriver interface {
+	Get(key string) (string, error)
+	Set(key string, value string, ttl time.Duration) error
+	SetNX(key string, value string, ttl time.Duration) (bool, error)
+	Incr(key string) (int64, error)
+	HSet(key string, fields map[string]interface{}) error
+	HGetAll(key string) (map[string]string, error)
+	Expire(key string, ttl time.Duration) error
+	LPush(key string, value string) error
+	LTrim(key string, start int64, stop int64) error
+	LRange(key string, start int64, stop int64) ([]string, error)
+	Del(key string) error
+	ZAdd(key string, score float64, member string) error
+	ZRem(key string, member string) error
+	ZRevRange(key string, start int64, stop int64) ([]string, error)
+	// SaveMapping writes the main key and its metadata hash in a single
+	// pipelined round trip
+	SaveMapping(write MappingWrite) error
+}
+
+func mappingWriteError(err error) error {
+	return fmt.Errorf("redis driver: failed saving mapping: %w", err)
+}
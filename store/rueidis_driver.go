@@ -0,0 +1,135 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// RueidisDriver implements RedisDriver on top of rueidis. Reads go
+// through DoCache so hot short codes are served out of rueidis' RESP3
+// client-side cache instead of round-tripping to Redis on every
+// redirect; writes use the command builder and are pipelined with
+// DoMulti where more than one key is touched
+type RueidisDriver struct {
+	client rueidis.Client
+}
+
+// NewRueidisDriver connects to the given Redis address(es) using rueidis
+func NewRueidisDriver(addr string) (*RueidisDriver, error) {
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress: []string{addr},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rueidis driver: failed connecting to redis: %w", err)
+	}
+	return &RueidisDriver{client: client}, nil
+}
+
+func (d *RueidisDriver) Get(key string) (string, error) {
+	cmd := d.client.B().Get().Key(key).Cache()
+	result, err := d.client.DoCache(ctx, cmd, ClientSideCacheTTL).ToString()
+	if rueidis.IsRedisNil(err) {
+		return "", ErrNotFound
+	}
+	return result, err
+}
+
+func (d *RueidisDriver) Set(key string, value string, ttl time.Duration) error {
+	cmd := d.client.B().Set().Key(key).Value(value).Ex(ttl).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) SetNX(key string, value string, ttl time.Duration) (bool, error) {
+	cmd := d.client.B().Set().Key(key).Value(value).Nx().Ex(ttl).Build()
+	result := d.client.Do(ctx, cmd)
+	if rueidis.IsRedisNil(result.Error()) {
+		return false, nil
+	}
+	if err := result.Error(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (d *RueidisDriver) Incr(key string) (int64, error) {
+	cmd := d.client.B().Incr().Key(key).Build()
+	return d.client.Do(ctx, cmd).ToInt64()
+}
+
+func (d *RueidisDriver) HSet(key string, fields map[string]interface{}) error {
+	builder := d.client.B().Hset().Key(key).FieldValue()
+	for field, value := range fields {
+		builder = builder.FieldValue(field, fmt.Sprintf("%v", value))
+	}
+	return d.client.Do(ctx, builder.Build()).Error()
+}
+
+func (d *RueidisDriver) HGetAll(key string) (map[string]string, error) {
+	cmd := d.client.B().Hgetall().Key(key).Build()
+	return d.client.Do(ctx, cmd).AsStrMap()
+}
+
+func (d *RueidisDriver) Expire(key string, ttl time.Duration) error {
+	cmd := d.client.B().Expire().Key(key).Seconds(int64(ttl.Seconds())).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) LPush(key string, value string) error {
+	cmd := d.client.B().Lpush().Key(key).Element(value).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) LTrim(key string, start int64, stop int64) error {
+	cmd := d.client.B().Ltrim().Key(key).Start(start).Stop(stop).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) LRange(key string, start int64, stop int64) ([]string, error) {
+	cmd := d.client.B().Lrange().Key(key).Start(start).Stop(stop).Build()
+	return d.client.Do(ctx, cmd).AsStrSlice()
+}
+
+func (d *RueidisDriver) Del(key string) error {
+	cmd := d.client.B().Del().Key(key).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) ZAdd(key string, score float64, member string) error {
+	cmd := d.client.B().Zadd().Key(key).ScoreMember().ScoreMember(score, member).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) ZRem(key string, member string) error {
+	cmd := d.client.B().Zrem().Key(key).Member(member).Build()
+	return d.client.Do(ctx, cmd).Error()
+}
+
+func (d *RueidisDriver) ZRevRange(key string, start int64, stop int64) ([]string, error) {
+	cmd := d.client.B().Zrevrange().Key(key).Start(start).Stop(stop).Build()
+	return d.client.Do(ctx, cmd).AsStrSlice()
+}
+
+// SaveMapping pipelines the main key and metadata hash writes together
+// with DoMulti instead of issuing one round trip per command
+func (d *RueidisDriver) SaveMapping(write MappingWrite) error {
+	setCmd := d.client.B().Set().Key(write.Key).Value(write.Value).Ex(write.TTL).Build()
+
+	hsetBuilder := d.client.B().Hset().Key(write.MetaKey).FieldValue()
+	for field, value := range write.MetaFields {
+		hsetBuilder = hsetBuilder.FieldValue(field, fmt.Sprintf("%v", value))
+	}
+	hsetCmd := hsetBuilder.Build()
+
+	expireCmd := d.client.B().Expire().Key(write.MetaKey).Seconds(int64(write.TTL.Seconds())).Build()
+
+	for _, result := range d.client.DoMulti(ctx, setCmd, hsetCmd, expireCmd) {
+		if err := result.Error(); err != nil {
+			return mappingWriteError(err)
+		}
+	}
+	return nil
+}
+
+var _ RedisDriver = (*RueidisDriver)(nil)
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ShortCodeCounterKey is the monotonic Redis counter short codes are
+// derived from, guaranteeing the code space is collision-free by
+// construction rather than relying on hash-based randomness
+const ShortCodeCounterKey = "shortlink:counter"
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// maxGenerateAttempts bounds how many times GenerateShortCode will retry
+// after landing on a code that's already taken by a custom alias, so a
+// degenerate keyspace can't spin forever
+const maxGenerateAttempts = 10
+
+// ErrAliasTaken is returned by ReserveAlias when the requested custom
+// alias is already in use
+var ErrAliasTaken = errors.New("store: alias already taken")
+
+// encodeBase62 turns a positive int64 into its Base62 representation
+// using the [0-9A-Za-z] alphabet
+func encodeBase62(n int64) string {
+	if n == 0 {
+		return string(base62Alphabet[0])
+	}
+
+	base := int64(len(base62Alphabet))
+	var encoded []byte
+	for n > 0 {
+		remainder := n % base
+		encoded = append([]byte{base62Alphabet[remainder]}, encoded...)
+		n /= base
+	}
+	return string(encoded)
+}
+
+// codeTaken reports whether shortUrl is already claimed in the durable
+// primary store. Both GenerateShortCode and ReserveAlias share this flat
+// keyspace, so neither can rely on the Redis cache alone: once a cache
+// entry's TTL expires independently of the (potentially much longer-lived)
+// primary record, a cache-only check would let a second caller silently
+// clobber it
+func codeTaken(shortUrl string) (bool, error) {
+	if storeService.primary == storeService.cache {
+		return false, nil
+	}
+	if _, err := storeService.primary.Get(shortUrl); err == nil {
+		return true, nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	return false, nil
+}
+
+// GenerateShortCode atomically reserves the next counter value and
+// returns its Base62 encoding. Counter-generated codes and custom
+// aliases share one flat keyspace, so each candidate is checked against
+// the durable primary store and reserved in the cache via SETNX before
+// being handed back; a collision with an existing alias simply draws the
+// next counter value
+func GenerateShortCode() string {
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		id, err := storeService.driver.Incr(ShortCodeCounterKey)
+		if err != nil {
+			panic(fmt.Sprintf("failed incrementing short code counter | Error %v:", err))
+		}
+		code := encodeBase62(id)
+
+		taken, err := codeTaken(code)
+		if err != nil {
+			panic(fmt.Sprintf("failed checking generated short code | Error %v:", err))
+		}
+		if taken {
+			continue
+		}
+
+		reserved, err := storeService.driver.SetNX(code, "", DefaultCacheDuration)
+		if err != nil {
+			panic(fmt.Sprintf("failed reserving generated short code | Error %v:", err))
+		}
+		if !reserved {
+			continue
+		}
+
+		return code
+	}
+	panic("failed generating short code: exhausted retries after repeated collisions")
+}
+
+// ReserveAlias atomically claims a caller-supplied custom alias, checking
+// both the durable primary store and the Redis cache. It returns
+// ErrAliasTaken when the alias is already in use - by either tier -
+// leaving the caller to retry with a different one rather than silently
+// overwriting an existing short link
+func ReserveAlias(alias string) error {
+	taken, err := codeTaken(alias)
+	if err != nil {
+		panic(fmt.Sprintf("failed checking alias | Error %v:", err))
+	}
+	if taken {
+		return ErrAliasTaken
+	}
+
+	reserved, err := storeService.driver.SetNX(alias, "", DefaultCacheDuration)
+	if err != nil {
+		panic(fmt.Sprintf("failed reserving alias | Error %v:", err))
+	}
+	if !reserved {
+		return ErrAliasTaken
+	}
+	return nil
+}
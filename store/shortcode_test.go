@@ -0,0 +1,26 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBase62(t *testing.T) {
+	assert.Equal(t, "0", encodeBase62(0))
+	assert.Equal(t, "1", encodeBase62(1))
+	assert.Equal(t, "10", encodeBase62(62))
+}
+
+func TestGenerateShortCodeIsUnique(t *testing.T) {
+	first := GenerateShortCode()
+	second := GenerateShortCode()
+	assert.NotEqual(t, first, second)
+}
+
+func TestReserveAliasRejectsDuplicate(t *testing.T) {
+	alias := "my-custom-alias"
+
+	assert.NoError(t, ReserveAlias(alias))
+	assert.ErrorIs(t, ReserveAlias(alias), ErrAliasTaken)
+}
@@ -2,15 +2,24 @@ package store
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"time"
-
-	"github.com/go-redis/redis/v8"
 )
 
-// define the struct wrapper around raw Redis client
+// define the struct wrapper around the store's dependencies. driver is the
+// pluggable Redis client (go-redis or rueidis) everything else in this
+// file is built on; cache is the fast, TTL-backed tier built on top of it;
+// primary is the durable backing store the cache is populated from on a
+// miss. When no durable store is configured, primary is the same
+// RedisStore as cache, reproducing the old single-tier behaviour
 type StorageService struct {
-	redisClient *redis.Client
+	driver  RedisDriver
+	cache   Backend
+	primary Backend
 }
 
 // top level declarations for the storeService and Redis context
@@ -24,42 +33,359 @@ var (
 // values that are retrieved less often are purged automatically from
 // the cache and stored back in RDBMS whenever the cache is full
 
-const CacheDuration = 6 * time.Hour
+const (
+	// DefaultCacheDuration is applied when the caller does not request
+	// a custom lifetime for a short link
+	DefaultCacheDuration = 6 * time.Hour
+	// MaxCacheDuration is the server-side ceiling on how long a caller
+	// can ask a short link to live for
+	MaxCacheDuration = 30 * 24 * time.Hour
+	// MaxVisitEvents bounds how many recent visit events we keep per
+	// short code, older events are trimmed off
+	MaxVisitEvents = 100
+)
 
-// initializing the store service and return a store pointer
-func InitializeStore() *StorageService {
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
-	})
+// clampExpiry applies the DefaultCacheDuration/MaxCacheDuration policy to a
+// caller-requested lifetime: non-positive values fall back to the default,
+// and anything past the server-side ceiling is clamped down to it rather
+// than discarded in favour of the (much shorter) default
+func clampExpiry(expiresIn time.Duration) time.Duration {
+	if expiresIn <= 0 {
+		return DefaultCacheDuration
+	}
+	if expiresIn > MaxCacheDuration {
+		return MaxCacheDuration
+	}
+	return expiresIn
+}
 
-	pong, err := redisClient.Ping(ctx).Result()
+// VisitEvent records a single redirect hit against a short code
+type VisitEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Referer   string    `json:"referer"`
+	UserAgent string    `json:"userAgent"`
+	IP        string    `json:"ip"`
+}
+
+// UrlStats is the aggregate view returned by GET /stats/:shortUrl
+type UrlStats struct {
+	ShortUrl     string       `json:"shortUrl"`
+	OriginalUrl  string       `json:"originalUrl"`
+	UserId       string       `json:"userId"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	ExpiresAt    time.Time    `json:"expiresAt"`
+	TotalClicks  int64        `json:"totalClicks"`
+	RecentVisits []VisitEvent `json:"recentVisits"`
+}
+
+// getEnv returns the environment variable named by key, or fallback when
+// it is unset or empty
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newRedisDriver builds the configured RedisDriver. REDIS_DRIVER selects
+// between "go-redis" (the default) and "rueidis", which trades the extra
+// dependency for client-side caching and pipelined writes
+func newRedisDriver() (RedisDriver, error) {
+	addr := getEnv("REDIS_ADDR", "localhost:6379")
+
+	switch driver := getEnv("REDIS_DRIVER", "go-redis"); driver {
+	case "rueidis":
+		return NewRueidisDriver(addr)
+	case "go-redis":
+		return NewGoRedisDriver(addr, getEnv("REDIS_PASSWORD", ""))
+	default:
+		return nil, fmt.Errorf("store: unknown REDIS_DRIVER %q", driver)
+	}
+}
+
+// initializing the store service and return a store pointer. Redis is
+// always wired up as the read-through cache; if POSTGRES_DSN is set, it
+// becomes the durable primary store and Redis is populated from it on a
+// cache miss, otherwise Redis itself is used as the primary, matching the
+// original cache-only behaviour
+func InitializeStore() *StorageService {
+	driver, err := newRedisDriver()
 	if err != nil {
 		panic(fmt.Sprintf("Error init Redis %v:", err))
 	}
 
-	fmt.Printf("\nRedis started successfully: pong message = {%s}", pong)
-	storeService.redisClient = redisClient
+	fmt.Printf("\nRedis started successfully using the %q driver", getEnv("REDIS_DRIVER", "go-redis"))
+	storeService.driver = driver
+	storeService.cache = NewRedisStore(driver)
+
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		postgresStore, err := NewPostgresStore(dsn)
+		if err != nil {
+			panic(fmt.Sprintf("Error init Postgres %v:", err))
+		}
+		fmt.Printf("\nPostgres started successfully as the durable backing store")
+		storeService.primary = postgresStore
+	} else {
+		storeService.primary = storeService.cache
+	}
+
 	return storeService
 }
 
+// metadataKey is where the per-link hash (owner, original url, timestamps)
+// is kept, separate from the plain shortUrl -> originalUrl string mapping
+// so stats/metadata lookups don't need to touch the hot redirect path
+func metadataKey(shortUrl string) string {
+	return fmt.Sprintf("short:%s", shortUrl)
+}
+
+func visitsKey(shortUrl string) string {
+	return fmt.Sprintf("stats:%s:visits", shortUrl)
+}
+
+func eventsKey(shortUrl string) string {
+	return fmt.Sprintf("stats:%s:events", shortUrl)
+}
+
 // we want to be able to save the mapping between the original url
-// and the generated url
-func SaveUrlMapping(shortUrl string, originalUrl string, userId string) {
-	err := storeService.redisClient.Set(ctx, shortUrl, originalUrl, CacheDuration).Err()
-	if err != nil {
+// and the generated url. expiresIn lets the caller request a custom
+// lifetime for the link; it is clamped to MaxCacheDuration and falls
+// back to DefaultCacheDuration when not provided. The mapping is
+// write-through: it lands in the durable primary store first, then the
+// Redis cache's main key and metadata hash are written together in a
+// single pipelined call
+func SaveUrlMapping(shortUrl string, originalUrl string, userId string, expiresIn time.Duration) {
+	expiresIn = clampExpiry(expiresIn)
+
+	if err := storeService.primary.Put(shortUrl, originalUrl, userId, expiresIn); err != nil {
 		panic(fmt.Sprintf("failed saving key url | Error %v:", err))
 	}
+
+	now := time.Now().UTC()
+	metadata := map[string]interface{}{
+		"userId":      userId,
+		"originalUrl": originalUrl,
+		"createdAt":   now.Format(time.RFC3339),
+		"expiresAt":   now.Add(expiresIn).Format(time.RFC3339),
+	}
+	write := MappingWrite{
+		Key:        shortUrl,
+		Value:      originalUrl,
+		MetaKey:    metadataKey(shortUrl),
+		MetaFields: metadata,
+		TTL:        expiresIn,
+	}
+	if err := storeService.driver.SaveMapping(write); err != nil {
+		panic(fmt.Sprintf("failed saving url mapping | Error %v:", err))
+	}
+
+	AddUserLink(userId, shortUrl, now)
+}
+
+// UpdateUrlMapping changes the destination and/or lifetime of an existing
+// short link while preserving its owner and original creation time. An
+// empty originalUrl or non-positive expiresIn leaves that field
+// unchanged
+func UpdateUrlMapping(shortUrl string, originalUrl string, expiresIn time.Duration) {
+	existing := GetUrlStats(shortUrl)
+
+	if originalUrl == "" {
+		originalUrl = existing.OriginalUrl
+	}
+	if expiresIn <= 0 {
+		if remaining := time.Until(existing.ExpiresAt); remaining > 0 {
+			expiresIn = remaining
+		} else {
+			expiresIn = DefaultCacheDuration
+		}
+	}
+	expiresIn = clampExpiry(expiresIn)
+
+	if err := storeService.primary.Put(shortUrl, originalUrl, existing.UserId, expiresIn); err != nil {
+		panic(fmt.Sprintf("failed updating key url | Error %v:", err))
+	}
+
+	metadata := map[string]interface{}{
+		"userId":      existing.UserId,
+		"originalUrl": originalUrl,
+		"createdAt":   existing.CreatedAt.Format(time.RFC3339),
+		"expiresAt":   time.Now().UTC().Add(expiresIn).Format(time.RFC3339),
+	}
+	write := MappingWrite{
+		Key:        shortUrl,
+		Value:      originalUrl,
+		MetaKey:    metadataKey(shortUrl),
+		MetaFields: metadata,
+		TTL:        expiresIn,
+	}
+	if err := storeService.driver.SaveMapping(write); err != nil {
+		panic(fmt.Sprintf("failed updating url mapping | Error %v:", err))
+	}
+}
+
+// DeleteUrlMapping removes a short link from both storage tiers along
+// with its metadata, visit stats and the owner's link index
+func DeleteUrlMapping(shortUrl string) {
+	existing := GetUrlStats(shortUrl)
+
+	if err := storeService.primary.Delete(shortUrl); err != nil {
+		panic(fmt.Sprintf("failed deleting key url | Error %v:", err))
+	}
+	if storeService.primary != storeService.cache {
+		if err := storeService.cache.Delete(shortUrl); err != nil {
+			panic(fmt.Sprintf("failed deleting key url | Error %v:", err))
+		}
+	}
+
+	if err := storeService.driver.Del(metadataKey(shortUrl)); err != nil {
+		panic(fmt.Sprintf("failed deleting url metadata | Error %v:", err))
+	}
+	if err := storeService.driver.Del(visitsKey(shortUrl)); err != nil {
+		panic(fmt.Sprintf("failed deleting visit counter | Error %v:", err))
+	}
+	if err := storeService.driver.Del(eventsKey(shortUrl)); err != nil {
+		panic(fmt.Sprintf("failed deleting visit events | Error %v:", err))
+	}
+
+	RemoveUserLink(existing.UserId, shortUrl)
+}
+
+// repopulateMetadataFromPrimary reads the full record for shortUrl out of
+// the durable primary store and rehydrates both the Redis cache's main
+// key and its metadata hash from it in a single pipelined write, so a
+// cache miss on either the redirect path (RetrieveInitialUrl) or the
+// metadata path (GetUrlStats) fixes the cache for both going forward.
+// Any error from the primary, ErrNotFound included, is returned as-is for
+// the caller to handle; a failure to write the record back into the
+// cache is treated as fatal here
+func repopulateMetadataFromPrimary(shortUrl string) (LinkMetadata, error) {
+	metadata, err := storeService.primary.GetMetadata(shortUrl)
+	if err != nil {
+		return LinkMetadata{}, err
+	}
+
+	expiresIn := time.Until(metadata.ExpiresAt)
+	if expiresIn <= 0 {
+		expiresIn = DefaultCacheDuration
+	}
+	write := MappingWrite{
+		Key:     shortUrl,
+		Value:   metadata.OriginalUrl,
+		MetaKey: metadataKey(shortUrl),
+		MetaFields: map[string]interface{}{
+			"userId":      metadata.UserId,
+			"originalUrl": metadata.OriginalUrl,
+			"createdAt":   metadata.CreatedAt.Format(time.RFC3339),
+			"expiresAt":   metadata.ExpiresAt.Format(time.RFC3339),
+		},
+		TTL: expiresIn,
+	}
+	if err := storeService.driver.SaveMapping(write); err != nil {
+		panic(fmt.Sprintf("failed repopulating cache | Error : %v", err))
+	}
+	return metadata, nil
 }
 
 // we should be able to retrieve the initial long url once the short is provided
 // this is when users will be calling the short link in the url, so what we need
-// to do is to retrieve the long url and think about redirect.
+// to do is to retrieve the long url and think about redirect. Redis is
+// checked first; on a cache miss we fall back to the durable primary
+// store and repopulate the cache so the next redirect is fast again
 func RetrieveInitialUrl(shortUrl string) string {
-	result, err := storeService.redisClient.Get(ctx, shortUrl).Result()
+	result, err := storeService.cache.Get(shortUrl)
+	if err == nil {
+		return result
+	}
+	if !errors.Is(err, ErrNotFound) {
+		panic(fmt.Sprintf("failed RetrieveInitialUrl url | Error : %v", err))
+	}
+	if storeService.primary == storeService.cache {
+		panic(fmt.Sprintf("failed RetrieveInitialUrl url | Error : %v", err))
+	}
+
+	metadata, err := repopulateMetadataFromPrimary(shortUrl)
 	if err != nil {
 		panic(fmt.Sprintf("failed RetrieveInitialUrl url | Error : %v", err))
 	}
-	return result
+	return metadata.OriginalUrl
+}
+
+// RecordVisit is called on every redirect through a short code: it bumps the
+// total click counter and pushes a capped, most-recent-first list of visit
+// events so GetUrlStats can surface basic click analytics
+func RecordVisit(shortUrl string, referer string, userAgent string, ip string) {
+	if _, err := storeService.driver.Incr(visitsKey(shortUrl)); err != nil {
+		panic(fmt.Sprintf("failed incrementing visit counter | Error %v:", err))
+	}
+
+	event := VisitEvent{
+		Timestamp: time.Now().UTC(),
+		Referer:   referer,
+		UserAgent: userAgent,
+		IP:        ip,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		panic(fmt.Sprintf("failed marshalling visit event | Error %v:", err))
+	}
+
+	if err := storeService.driver.LPush(eventsKey(shortUrl), string(payload)); err != nil {
+		panic(fmt.Sprintf("failed pushing visit event | Error %v:", err))
+	}
+	if err := storeService.driver.LTrim(eventsKey(shortUrl), 0, MaxVisitEvents-1); err != nil {
+		panic(fmt.Sprintf("failed trimming visit events | Error %v:", err))
+	}
+}
+
+// GetUrlStats gathers the metadata hash, total click count and recent visit
+// events recorded for a short code into a single response-shaped struct.
+// The metadata hash is read from the Redis cache first; on a miss it
+// falls back to the durable primary store and repopulates the cache,
+// exactly like RetrieveInitialUrl, so a link whose cache metadata TTL
+// expired independently of its primary row still resolves correctly for
+// stats, update and delete
+func GetUrlStats(shortUrl string) UrlStats {
+	metadata, err := storeService.cache.GetMetadata(shortUrl)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			panic(fmt.Sprintf("failed retrieving url metadata | Error %v:", err))
+		}
+		if storeService.primary != storeService.cache {
+			repopulated, repErr := repopulateMetadataFromPrimary(shortUrl)
+			if repErr != nil && !errors.Is(repErr, ErrNotFound) {
+				panic(fmt.Sprintf("failed retrieving url metadata | Error %v:", repErr))
+			}
+			metadata = repopulated
+		}
+	}
+
+	var totalClicks int64
+	if raw, err := storeService.driver.Get(visitsKey(shortUrl)); err == nil {
+		totalClicks, _ = strconv.ParseInt(raw, 10, 64)
+	} else if !errors.Is(err, ErrNotFound) {
+		panic(fmt.Sprintf("failed retrieving visit counter | Error %v:", err))
+	}
+
+	rawEvents, err := storeService.driver.LRange(eventsKey(shortUrl), 0, MaxVisitEvents-1)
+	if err != nil {
+		panic(fmt.Sprintf("failed retrieving visit events | Error %v:", err))
+	}
+	recentVisits := make([]VisitEvent, 0, len(rawEvents))
+	for _, raw := range rawEvents {
+		var event VisitEvent
+		if err := json.Unmarshal([]byte(raw), &event); err != nil {
+			continue
+		}
+		recentVisits = append(recentVisits, event)
+	}
+
+	return UrlStats{
+		ShortUrl:     shortUrl,
+		OriginalUrl:  metadata.OriginalUrl,
+		UserId:       metadata.UserId,
+		CreatedAt:    metadata.CreatedAt,
+		ExpiresAt:    metadata.ExpiresAt,
+		TotalClicks:  totalClicks,
+		RecentVisits: recentVisits,
+	}
 }
@@ -2,6 +2,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +14,7 @@ func init() {
 }
 
 func TestStoreInit(t *testing.T) {
-	assert.True(t, testStoreService.redisClient != nil)
+	assert.True(t, testStoreService.driver != nil)
 }
 
 func TestInsertionAndRetrieval(t *testing.T) {
@@ -22,9 +23,63 @@ func TestInsertionAndRetrieval(t *testing.T) {
 	shortUrl := "Jsz4k57oAX"
 
 	// presist data mapping
-	SaveUrlMapping(shortUrl, initialLink, userUUId)
+	SaveUrlMapping(shortUrl, initialLink, userUUId, DefaultCacheDuration)
 
 	// retrieve initial url
 	retrievedUrl := RetrieveInitialUrl(shortUrl)
 	assert.Equal(t, initialLink, retrievedUrl)
 }
+
+func TestSaveUrlMappingClampsExpiryToMax(t *testing.T) {
+	shortUrl := "Tt9fQ2eVXp"
+
+	SaveUrlMapping(shortUrl, "https://example.com", "e0dba740-fc4b-4977-872c-d360239e6b1a", MaxCacheDuration*2)
+
+	stats := GetUrlStats(shortUrl)
+	assert.True(t, stats.ExpiresAt.Before(time.Now().UTC().Add(MaxCacheDuration+time.Minute)))
+}
+
+func TestRecordVisitAndGetUrlStats(t *testing.T) {
+	shortUrl := "Wk3pL8mNop"
+	userUUId := "e0dba740-fc4b-4977-872c-d360239e6b1a"
+
+	SaveUrlMapping(shortUrl, "https://example.com", userUUId, DefaultCacheDuration)
+	RecordVisit(shortUrl, "https://referer.example.com", "test-agent", "127.0.0.1")
+	RecordVisit(shortUrl, "https://referer.example.com", "test-agent", "127.0.0.1")
+
+	stats := GetUrlStats(shortUrl)
+	assert.Equal(t, userUUId, stats.UserId)
+	assert.Equal(t, "https://example.com", stats.OriginalUrl)
+	assert.EqualValues(t, 2, stats.TotalClicks)
+	assert.Len(t, stats.RecentVisits, 2)
+	assert.WithinDuration(t, time.Now().UTC(), stats.CreatedAt, time.Minute)
+}
+
+func TestUpdateUrlMappingPreservesOwnerAndCreatedAt(t *testing.T) {
+	shortUrl := "Qx7mZ2kLpW"
+	userUUId := "e0dba740-fc4b-4977-872c-d360239e6b1a"
+
+	SaveUrlMapping(shortUrl, "https://example.com", userUUId, DefaultCacheDuration)
+	original := GetUrlStats(shortUrl)
+
+	UpdateUrlMapping(shortUrl, "https://example.com/updated", 0)
+
+	updated := GetUrlStats(shortUrl)
+	assert.Equal(t, "https://example.com/updated", updated.OriginalUrl)
+	assert.Equal(t, userUUId, updated.UserId)
+	assert.Equal(t, original.CreatedAt, updated.CreatedAt)
+}
+
+func TestDeleteUrlMappingRemovesLinkAndIndex(t *testing.T) {
+	shortUrl := "Hn4vC9rTqJ"
+	userUUId := "e0dba740-fc4b-4977-872c-d360239e6b1a"
+
+	SaveUrlMapping(shortUrl, "https://example.com", userUUId, DefaultCacheDuration)
+	assert.Contains(t, ListUserLinks(userUUId, 0, 100), shortUrl)
+
+	DeleteUrlMapping(shortUrl)
+
+	_, err := storeService.cache.Get(shortUrl)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotContains(t, ListUserLinks(userUUId, 0, 100), shortUrl)
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// userLinksKey is a sorted set of every short code a user owns, scored by
+// creation time so the most recent links can be listed first
+func userLinksKey(userId string) string {
+	return fmt.Sprintf("user:%s:links", userId)
+}
+
+// AddUserLink indexes shortUrl under userId's link list. Anonymous links
+// (no userId) are not indexed, there being no owner to list them for
+func AddUserLink(userId string, shortUrl string, createdAt time.Time) {
+	if userId == "" {
+		return
+	}
+	if err := storeService.driver.ZAdd(userLinksKey(userId), float64(createdAt.Unix()), shortUrl); err != nil {
+		panic(fmt.Sprintf("failed indexing user link | Error %v:", err))
+	}
+}
+
+// RemoveUserLink drops shortUrl from userId's link list, called once the
+// short link itself has been deleted
+func RemoveUserLink(userId string, shortUrl string) {
+	if userId == "" {
+		return
+	}
+	if err := storeService.driver.ZRem(userLinksKey(userId), shortUrl); err != nil {
+		panic(fmt.Sprintf("failed removing user link | Error %v:", err))
+	}
+}
+
+// ListUserLinks returns up to limit short codes owned by userId, most
+// recently created first, starting at offset
+func ListUserLinks(userId string, offset int64, limit int64) []string {
+	links, err := storeService.driver.ZRevRange(userLinksKey(userId), offset, offset+limit-1)
+	if err != nil {
+		panic(fmt.Sprintf("failed listing user links | Error %v:", err))
+	}
+	return links
+}